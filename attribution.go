@@ -0,0 +1,136 @@
+package mailstrip
+
+import (
+	"regexp"
+	"strings"
+	"time"
+)
+
+// QuoteHeader holds the sender attribution mailstrip was able to parse out
+// of a quoted Fragment's header line, such as "On Jan 2, 2011, at 7:37 PM,
+// Alice <alice@example.com> wrote:" or an Outlook "From:/Sent:/To:/Subject:"
+// block. It is the zero value when the Fragment isn't Quoted(), or its
+// header didn't match one of the known shapes.
+type QuoteHeader struct {
+	// Raw is the header line (or lines, for the Outlook block) exactly as
+	// it appeared in the email.
+	Raw string
+
+	// From is the sender's name and/or e-mail address, as written in the
+	// header, e.g. "Alice <alice@example.com>".
+	From string
+
+	// Date is the parsed send date. It is the zero time.Time if none of
+	// mailstrip's date layouts matched.
+	Date time.Time
+}
+
+var (
+	// "On Jan 2, 2011, at 7:37 PM, Alice <alice@example.com> wrote:"
+	onWroteHeaderRegexp = regexp.MustCompile(`(?i)^On (.+), ([^,]+) wrote:$`)
+	// "2013/11/13 John Smith <john@smith.org>"
+	dateNameHeaderRegexp = regexp.MustCompile(`^(\d{4}/\d{2}/\d{2}) (.+)$`)
+	// "El 3 de enero de 2020, Alice <alice@example.com> escribió:"
+	elEscribioHeaderRegexp = regexp.MustCompile(`(?i)^El (.+), ([^,]+) escribió:$`)
+
+	// The Outlook quote block: a "From:" line, optionally followed by
+	// "Sent:", "To:", and "Subject:" lines in any order.
+	outlookFromRegexp = regexp.MustCompile(`(?im)^From:\s*(.+)$`)
+	outlookSentRegexp = regexp.MustCompile(`(?im)^Sent:\s*(.+)$`)
+
+	// headerDateLayouts are tried in order by parseHeaderDate. They cover
+	// RFC 5322 dates plus the common locale-specific shapes seen in the
+	// email_reply_parser fixture corpus.
+	headerDateLayouts = []string{
+		time.RFC1123Z,
+		time.RFC1123,
+		"Jan 2, 2006, at 3:04 PM",
+		"Jan 2, 2006 at 3:04 PM",
+		"Jan 2, 2006",
+		"January 2, 2006 at 3:04 PM",
+		"January 2, 2006",
+		"1/2/2006, 3:04 PM",
+		"2006/01/02",
+		"2 January 2006",
+		"2. January 2006",
+		"02.01.2006",
+		"02.01.2006 15:04",
+		"2 de January de 2006",
+	}
+
+	// localizedMonths maps German and Spanish month names to their English
+	// equivalents so time.Parse -- which only ever recognizes English month
+	// names, no matter the layout string -- can match them against the
+	// "2. January 2006" / "2 de January de 2006" layouts above.
+	localizedMonths = map[string]string{
+		"januar": "January", "februar": "February", "märz": "March", "april": "April",
+		"mai": "May", "juni": "June", "juli": "July", "august": "August",
+		"september": "September", "oktober": "October", "november": "November", "dezember": "December",
+
+		"enero": "January", "febrero": "February", "marzo": "March", "abril": "April",
+		"mayo": "May", "junio": "June", "julio": "July", "agosto": "August",
+		"septiembre": "September", "octubre": "October", "noviembre": "November", "diciembre": "December",
+	}
+	wordRegexp = regexp.MustCompile(`(?i)\pL+`)
+)
+
+// parseQuoteHeader attempts to parse attribution metadata out of content,
+// the full text of a quoted Fragment. It returns the zero QuoteHeader if no
+// known header shape is found.
+func parseQuoteHeader(content string) QuoteHeader {
+	first := strings.TrimSpace(firstLine(content))
+
+	for _, re := range []*regexp.Regexp{onWroteHeaderRegexp, elEscribioHeaderRegexp} {
+		if m := re.FindStringSubmatch(first); m != nil {
+			return QuoteHeader{Raw: first, Date: parseHeaderDate(m[1]), From: strings.TrimSpace(m[2])}
+		}
+	}
+	if m := dateNameHeaderRegexp.FindStringSubmatch(first); m != nil {
+		return QuoteHeader{Raw: first, Date: parseHeaderDate(m[1]), From: strings.TrimSpace(m[2])}
+	}
+
+	if m := outlookFromRegexp.FindStringSubmatch(content); m != nil {
+		h := QuoteHeader{Raw: strings.TrimSpace(m[0]), From: strings.TrimSpace(m[1])}
+		if sm := outlookSentRegexp.FindStringSubmatch(content); sm != nil {
+			h.Date = parseHeaderDate(strings.TrimSpace(sm[1]))
+			h.Raw = h.Raw + "\n" + strings.TrimSpace(sm[0])
+		}
+		return h
+	}
+
+	return QuoteHeader{}
+}
+
+// parseHeaderDate tries each of headerDateLayouts in turn, returning the
+// zero time.Time if none of them match s.
+func parseHeaderDate(s string) time.Time {
+	s = translateMonth(strings.TrimSpace(s))
+	for _, layout := range headerDateLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t
+		}
+	}
+	return time.Time{}
+}
+
+// translateMonth replaces any German or Spanish month name in s with its
+// English equivalent, leaving everything else (including already-English
+// input) untouched.
+func translateMonth(s string) string {
+	return wordRegexp.ReplaceAllStringFunc(s, func(word string) string {
+		if en, ok := localizedMonths[strings.ToLower(word)]; ok {
+			return en
+		}
+		return word
+	})
+}
+
+// firstLine returns the first non-blank line of s.
+func firstLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		if strings.TrimSpace(line) != "" {
+			return line
+		}
+	}
+	return ""
+}
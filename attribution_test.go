@@ -0,0 +1,91 @@
+package mailstrip
+
+import (
+	"testing"
+	"time"
+)
+
+func TestQuoteHeaderOnWrote(t *testing.T) {
+	text := `Sounds good.
+
+On Jan 2, 2011, Alice <alice@example.com> wrote:
+> Can we push the release?
+`
+
+	email := Parse(text)
+	var quoted *Fragment
+	for _, f := range email {
+		if f.Quoted() {
+			quoted = f
+			break
+		}
+	}
+	if quoted == nil {
+		t.Fatal("no quoted fragment found")
+	}
+
+	h := quoted.QuoteHeader()
+	if got, want := h.From, "Alice <alice@example.com>"; got != want {
+		t.Errorf("From = %q, want %q", got, want)
+	}
+	if h.Date.IsZero() {
+		t.Errorf("Date is zero, want parsed date")
+	}
+}
+
+func TestQuoteHeaderDateName(t *testing.T) {
+	email := Parse("Thanks!\n\n2013/11/13 John Smith <john@smith.org>\n> the body\n")
+	for _, f := range email {
+		if f.Quoted() {
+			h := f.QuoteHeader()
+			if got, want := h.From, "John Smith <john@smith.org>"; got != want {
+				t.Errorf("From = %q, want %q", got, want)
+			}
+			return
+		}
+	}
+	t.Fatal("no quoted fragment found")
+}
+
+func TestQuoteHeaderElEscribioDate(t *testing.T) {
+	text := `Perfecto, gracias.
+
+El 3 de enero de 2020, Alice <alice@example.com> escribió:
+> ¿Podemos adelantar el lanzamiento?
+`
+
+	email := ParseWithLocales(text, LocaleSpanish)
+	for _, f := range email {
+		if f.Quoted() {
+			h := f.QuoteHeader()
+			if got, want := h.From, "Alice <alice@example.com>"; got != want {
+				t.Errorf("From = %q, want %q", got, want)
+			}
+			if h.Date.IsZero() {
+				t.Fatal("Date is zero, want parsed date")
+			}
+			if got, want := h.Date.Month(), time.January; got != want {
+				t.Errorf("Date.Month() = %v, want %v", got, want)
+			}
+			if got, want := h.Date.Day(), 3; got != want {
+				t.Errorf("Date.Day() = %d, want %d", got, want)
+			}
+			return
+		}
+	}
+	t.Fatal("no quoted fragment found")
+}
+
+func TestQuoteHeaderUnrecognizedIsZero(t *testing.T) {
+	email := Parse("Hi\n\n> just a quote, no header\n")
+	for _, f := range email {
+		if f.Quoted() {
+			h := f.QuoteHeader()
+			if h.From != "" || !h.Date.IsZero() {
+				t.Errorf("got non-zero QuoteHeader %+v for an unrecognized header line", h)
+			}
+			return
+		}
+	}
+	t.Fatal("no quoted fragment found")
+}
@@ -0,0 +1,207 @@
+package mailstrip
+
+import (
+	"bytes"
+	"regexp"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// ParseHTML parses an HTML email and returns the results, stripping quoted
+// replies and signatures the same way Parse does for plaintext. It
+// recognizes the quote-block conventions of the major mail clients:
+// <blockquote type="cite"> (Apple Mail and most generic clients), Gmail's
+// div.gmail_quote, Outlook's #divRplyFwdMsg and hr#stopSpelling, and the
+// div.gmail_signature / trailing "-- " signature separators.
+//
+// Each returned Fragment carries both a plaintext String() and an HTML()
+// rendering, and Email.String()/Email.HTML() both emit only the fragments
+// that aren't Hidden(), mirroring Parse's semantics.
+func ParseHTML(htmlText string) (Email, error) {
+	doc, err := html.Parse(strings.NewReader(htmlText))
+	if err != nil {
+		return nil, err
+	}
+
+	p := &htmlParser{}
+	p.walk(doc)
+	p.finishFragment()
+	return Email(p.fragments), nil
+}
+
+// ParseMessage parses an email body in either plaintext or HTML, dispatching
+// on contentType (as found in a MIME Content-Type header, e.g.
+// "text/html; charset=utf-8"). It calls ParseHTML for "text/html" bodies and
+// Parse for everything else.
+func ParseMessage(body, contentType string) (Email, error) {
+	if strings.Contains(strings.ToLower(contentType), "text/html") {
+		return ParseHTML(body)
+	}
+	return Parse(body), nil
+}
+
+// htmlParser walks the parsed HTML tree, splitting it into fragments the
+// same way parser splits plaintext into lines: a run of nodes belongs to one
+// Fragment until a quote block, signature block, or stop-spelling marker
+// starts a new one.
+type htmlParser struct {
+	fragment  *htmlFragment
+	fragments []*Fragment
+}
+
+type htmlFragment struct {
+	nodes     []*html.Node
+	quoted    bool
+	signature bool
+}
+
+func (p *htmlParser) walk(n *html.Node) {
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		switch {
+		case isQuoteContainer(c):
+			p.finishFragment()
+			p.addFragment(c, true, false)
+		case isSignatureContainer(c):
+			p.finishFragment()
+			p.addFragment(c, false, true)
+		case isStopSpelling(c):
+			// Outlook marks the boundary between reply and quoted content
+			// with an <hr id="stopSpelling">; the hr's following siblings,
+			// however they're marked up, are quoted. Content after the hr's
+			// parent element is walked as usual, not marked quoted.
+			p.finishFragment()
+			p.fragment = &htmlFragment{quoted: true}
+			for s := c.NextSibling; s != nil; s = s.NextSibling {
+				p.fragment.nodes = append(p.fragment.nodes, s)
+			}
+			p.finishFragment()
+			return
+		case c.Type == html.ElementNode && c.FirstChild != nil:
+			// Descend into any element with children, not just html/head/body:
+			// real mail markup nests the quote/signature boundary arbitrarily
+			// deep inside wrapper divs (e.g. Gmail's <div dir="ltr">reply<div
+			// class="gmail_quote">...</div></div>), so it has to be found
+			// regardless of how many wrappers surround it.
+			p.walk(c)
+		default:
+			if p.fragment == nil {
+				p.fragment = &htmlFragment{}
+			}
+			p.fragment.nodes = append(p.fragment.nodes, c)
+		}
+	}
+}
+
+// addFragment starts and immediately finishes a new one-node fragment for
+// n, so quote/signature blocks never get merged with surrounding visible
+// content. It replaces p.fragment, so callers finish the current fragment
+// first.
+func (p *htmlParser) addFragment(n *html.Node, quoted, signature bool) {
+	p.fragment = &htmlFragment{nodes: []*html.Node{n}, quoted: quoted, signature: signature}
+	p.finishFragment()
+}
+
+// finishFragment renders the accumulated nodes into a Fragment and appends
+// it. Quoted and signature fragments are hidden, as are empty ones, wherever
+// they fall in the document; unlike markHidden for plaintext, a quote above
+// visible content is hidden too.
+func (p *htmlParser) finishFragment() {
+	if p.fragment == nil || len(p.fragment.nodes) == 0 {
+		p.fragment = nil
+		return
+	}
+
+	var htmlBuf, textBuf bytes.Buffer
+	for _, n := range p.fragment.nodes {
+		html.Render(&htmlBuf, n)
+		textBuf.WriteString(renderText(n))
+	}
+
+	text := strings.TrimSpace(textBuf.String())
+	f := &Fragment{
+		content:     text,
+		htmlContent: strings.TrimSpace(htmlBuf.String()),
+		quoted:      p.fragment.quoted,
+		signature:   p.fragment.signature,
+		hidden:      p.fragment.quoted || p.fragment.signature || text == "",
+	}
+	if f.quoted {
+		f.header = parseQuoteHeader(text)
+	}
+	p.fragments = append(p.fragments, f)
+	p.fragment = nil
+}
+
+// renderText extracts n's visible text, treating <br>, <p>, and <div> as
+// line breaks the same way a mail client would render them.
+func renderText(n *html.Node) string {
+	if n.Type == html.TextNode {
+		return n.Data
+	}
+
+	var buf bytes.Buffer
+	for c := n.FirstChild; c != nil; c = c.NextSibling {
+		buf.WriteString(renderText(c))
+		if c.Type == html.ElementNode && (c.Data == "br" || c.Data == "p" || c.Data == "div") {
+			buf.WriteString("\n")
+		}
+	}
+	return buf.String()
+}
+
+func isQuoteContainer(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	switch n.Data {
+	case "blockquote":
+		return true
+	case "div":
+		return hasClass(n, "gmail_quote") || hasID(n, "divRplyFwdMsg")
+	}
+	return false
+}
+
+// sigSeparatorRegexp matches the plaintext "--"/"-- " signature separator
+// (see sigRegexp) as the first line of an element's rendered text, e.g.
+// <div>-- <br>John</div>.
+var sigSeparatorRegexp = regexp.MustCompile(`^--\s?$`)
+
+func isSignatureContainer(n *html.Node) bool {
+	if n.Type != html.ElementNode {
+		return false
+	}
+	if n.Data == "div" && hasClass(n, "gmail_signature") {
+		return true
+	}
+	first, _, _ := strings.Cut(renderText(n), "\n")
+	return sigSeparatorRegexp.MatchString(first)
+}
+
+func isStopSpelling(n *html.Node) bool {
+	return n.Type == html.ElementNode && n.Data == "hr" && hasID(n, "stopSpelling")
+}
+
+func hasClass(n *html.Node, class string) bool {
+	for _, a := range n.Attr {
+		if a.Key != "class" {
+			continue
+		}
+		for _, c := range strings.Fields(a.Val) {
+			if c == class {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func hasID(n *html.Node, id string) bool {
+	for _, a := range n.Attr {
+		if a.Key == "id" && a.Val == id {
+			return true
+		}
+	}
+	return false
+}
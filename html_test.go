@@ -0,0 +1,102 @@
+package mailstrip
+
+import "testing"
+
+func TestParseHTMLGmailQuote(t *testing.T) {
+	text := `<div dir="ltr">Sounds good, see you then!</div>
+<div class="gmail_quote">
+  <div dir="ltr">On Mon, Jan 3, 2011 at 7:07 PM Alice &lt;alice@example.com&gt; wrote:</div>
+  <blockquote class="gmail_quote">Can we push the release?</blockquote>
+</div>`
+
+	email, err := ParseHTML(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(email) != 2 {
+		t.Fatalf("got %d fragments, want 2", len(email))
+	}
+	if email[0].Hidden() {
+		t.Errorf("fragment 0: Hidden() = true, want false")
+	}
+	if !email[1].Quoted() || !email[1].Hidden() {
+		t.Errorf("fragment 1: Quoted() = %t, Hidden() = %t, want true, true", email[1].Quoted(), email[1].Hidden())
+	}
+
+	if got, want := email.String(), "Sounds good, see you then!"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestParseHTMLGmailQuoteNestedInWrapper(t *testing.T) {
+	// Same markup as TestParseHTMLGmailQuote, but wrapped in an extra <div>
+	// the way Gmail actually nests it -- the reply text and div.gmail_quote
+	// as siblings inside a wrapper div, rather than siblings of <body>.
+	text := `<div dir="ltr"><div dir="ltr">Sounds good, see you then!</div>
+<div class="gmail_quote">
+  <div dir="ltr">On Mon, Jan 3, 2011 at 7:07 PM Alice &lt;alice@example.com&gt; wrote:</div>
+  <blockquote class="gmail_quote">Can we push the release?</blockquote>
+</div></div>`
+
+	email, err := ParseHTML(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(email) != 2 {
+		t.Fatalf("got %d fragments, want 2", len(email))
+	}
+	if email[0].Hidden() {
+		t.Errorf("fragment 0: Hidden() = true, want false")
+	}
+	if !email[1].Quoted() || !email[1].Hidden() {
+		t.Errorf("fragment 1: Quoted() = %t, Hidden() = %t, want true, true", email[1].Quoted(), email[1].Hidden())
+	}
+
+	if got, want := email.String(), "Sounds good, see you then!"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestParseHTMLBareSignatureSeparator(t *testing.T) {
+	text := `<div dir="ltr">Sounds good, see you then!</div>
+<div>-- <br>John</div>`
+
+	email, err := ParseHTML(text)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(email) != 2 {
+		t.Fatalf("got %d fragments, want 2", len(email))
+	}
+	if email[0].Hidden() {
+		t.Errorf("fragment 0: Hidden() = true, want false")
+	}
+	if !email[1].Signature() || !email[1].Hidden() {
+		t.Errorf("fragment 1: Signature() = %t, Hidden() = %t, want true, true", email[1].Signature(), email[1].Hidden())
+	}
+
+	if got, want := email.String(), "Sounds good, see you then!"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestParseMessageDispatchesOnContentType(t *testing.T) {
+	email, err := ParseMessage("<div>Hi there</div>", "text/html; charset=utf-8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := email.String(), "Hi there"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	email, err = ParseMessage("Hi there", "text/plain; charset=utf-8")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got, want := email.String(), "Hi there"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
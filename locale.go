@@ -0,0 +1,84 @@
+package mailstrip
+
+import (
+	"regexp"
+	"strings"
+)
+
+// Locale groups the regular expressions used to recognize quote headers,
+// signature markers, and forwarded-message banners for a particular
+// language. Pass one or more to ParseWithLocales to parse mail in languages
+// mailstrip doesn't assume by default.
+//
+// Any of the fields may be left nil if a locale doesn't need to add a
+// pattern for that category.
+type Locale struct {
+	// Name identifies the locale, e.g. "en", "de", "fr", "es". It isn't used
+	// for matching; it's there so callers can refer to a profile by name.
+	Name string
+
+	// QuoteHeader matches a line introducing a quoted reply, such as
+	// "On Jan 2, 2011, Alice <alice@example.com> wrote:" or the German
+	// "Am 2. Januar 2011 schrieb Alice <alice@example.com>:".
+	QuoteHeader *regexp.Regexp
+
+	// Signature matches an additional signature marker beyond the generic
+	// "--"/"__" conventions, such as "Sent from my iPhone" or the German
+	// "Von meinem iPhone gesendet".
+	Signature *regexp.Regexp
+
+	// Forwarded matches a forwarded-message banner, such as
+	// "---------- Forwarded message ----------".
+	Forwarded *regexp.Regexp
+}
+
+// Built-in locales for the languages mailstrip ships support for. Register
+// more with RegisterLocale, or pass custom ones directly to
+// ParseWithLocales.
+var (
+	LocaleEnglish = Locale{
+		Name:        "en",
+		QuoteHeader: regexp.MustCompile(quoteHeaderPattern("On", "wrote:")),
+		Signature:   regexp.MustCompile(`(?m)^Sent from my.*$`),
+		Forwarded:   regexp.MustCompile(`(?mi)^--+\s*Forwarded message\s*--+$`),
+	}
+	LocaleGerman = Locale{
+		Name:        "de",
+		QuoteHeader: regexp.MustCompile(quoteHeaderPattern("Am", "schrieb", ":")),
+		Signature:   regexp.MustCompile(`(?m)^Von meinem.*$`),
+		Forwarded:   regexp.MustCompile(`(?mi)^--+\s*Weitergeleitete Nachricht\s*--+$`),
+	}
+	LocaleFrench = Locale{
+		Name:        "fr",
+		QuoteHeader: regexp.MustCompile(quoteHeaderPattern("Le", "a écrit :")),
+		Signature:   regexp.MustCompile(`(?m)^Envoyé de mon.*$`),
+		Forwarded:   regexp.MustCompile(`(?mi)^--+\s*Message transféré\s*--+$`),
+	}
+	LocaleSpanish = Locale{
+		Name:        "es",
+		QuoteHeader: regexp.MustCompile(quoteHeaderPattern("El", "escribió:")),
+		Signature:   regexp.MustCompile(`(?m)^Enviado desde mi.*$`),
+		Forwarded:   regexp.MustCompile(`(?mi)^--+\s*Mensaje reenviado\s*--+$`),
+	}
+
+	// BuiltinLocales lists the locales mailstrip ships, in case callers want
+	// to pass all of them to ParseWithLocales without naming each one.
+	BuiltinLocales = []Locale{LocaleEnglish, LocaleGerman, LocaleFrench, LocaleSpanish}
+
+	registeredLocales []Locale
+)
+
+// RegisterLocale adds loc to the set of locales ParseWithLocales uses when
+// called without explicit locales, so a caller that always deals with the
+// same set of languages doesn't have to pass it at every call site.
+func RegisterLocale(loc Locale) {
+	registeredLocales = append(registeredLocales, loc)
+}
+
+// quoteHeaderPattern builds a quoteHeaderRegexp-style pattern matching a
+// quote header out of its fixed literal parts, in reading order, e.g.
+// ("On", "wrote:") for "On ... wrote:". Arbitrary text in between (dates,
+// names, e-mail addresses) is matched with ".*".
+func quoteHeaderPattern(literals ...string) string {
+	return "(?m)^" + strings.Join(literals, ".*") + "$"
+}
@@ -0,0 +1,43 @@
+package mailstrip
+
+import "testing"
+
+func TestParseWithLocalesGerman(t *testing.T) {
+	text := `Klingt gut, bis morgen!
+
+Am 03.02.2014 um 17:32 schrieb Alice <alice@example.com>:
+> Sollen wir uns morgen treffen?
+`
+
+	email := ParseWithLocales(text, LocaleGerman)
+	if got, want := email.String(), "Klingt gut, bis morgen!"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	// 3, not 2: the visible greeting, the quoted reply, and the trailing
+	// blank-line fragment the parser emits when the text's last line is
+	// quoted and followed by "\n".
+	if len(email) != 3 {
+		t.Fatalf("got %d fragments, want 3", len(email))
+	}
+	if !email[1].Quoted() {
+		t.Errorf("fragment 1: Quoted() = false, want true")
+	}
+	if !email[1].Hidden() {
+		t.Errorf("fragment 1: Hidden() = false, want true")
+	}
+}
+
+func TestParseWithoutLocalesIgnoresForeignHeaders(t *testing.T) {
+	text := `Klingt gut, bis morgen!
+
+Am 03.02.2014 um 17:32 schrieb Alice <alice@example.com>:
+> Sollen wir uns morgen treffen?
+`
+
+	email := Parse(text)
+	want := "Klingt gut, bis morgen!\n\nAm 03.02.2014 um 17:32 schrieb Alice <alice@example.com>:"
+	if got := email.String(); got != want {
+		t.Errorf("String() = %q, want %q (no locale registered to split the German quote header)", got, want)
+	}
+}
@@ -6,8 +6,6 @@
 package mailstrip
 
 import (
-	"bufio"
-	"fmt"
 	"io"
 	"regexp"
 	"strings"
@@ -20,30 +18,87 @@ func Parse(text string) Email {
 	return p.Parse(text)
 }
 
+// ParseWithLocales parses a plaintext email the same way Parse does, but
+// also recognizes quote headers, signatures, and forwarded-message banners
+// from the given locales, in addition to the package defaults. This lets
+// callers handling multilingual mail strip "Am ... schrieb ...:" or
+// "Le ... a écrit :" quote headers without forking the package.
+//
+// If no locales are given, it falls back to whatever was registered with
+// RegisterLocale.
+func ParseWithLocales(text string, locales ...Locale) Email {
+	if len(locales) == 0 {
+		locales = registeredLocales
+	}
+	p := &parser{locales: locales}
+	return p.Parse(text)
+}
+
+// ParseReader parses a plaintext email read in full from r and returns the
+// results, or an error if r could not be read.
+func ParseReader(r io.Reader) (Email, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	return Parse(string(data)), nil
+}
+
+// ParseStream parses a plaintext email read from r, sending each Fragment on
+// the returned channel as soon as it's found, instead of making the caller
+// wait for the whole Email to come back from Parse.
+//
+// Quote and signature detection fundamentally works bottom-up -- a
+// Fragment can only be recognized once the blank line (or end of message)
+// following it has been seen, the same way Parse's first pass does it --
+// so ParseStream still has to read all of r before it can send anything,
+// and Fragments arrive in last-to-first order, the order they're
+// discovered in. Likewise, Fragment.Hidden() can't be determined until the
+// whole message has been scanned, so it's always false on a streamed
+// Fragment; callers that need it should use Parse or ParseReader instead.
+//
+// The channel is closed once r has been fully scanned or a read error
+// occurs; in the latter case the error is sent to errs (if non-nil) before
+// the channel closes.
+func ParseStream(r io.Reader, errs chan<- error) <-chan *Fragment {
+	out := make(chan *Fragment)
+
+	go func() {
+		defer close(out)
+
+		data, err := io.ReadAll(r)
+		if err != nil {
+			if errs != nil {
+				errs <- err
+			}
+			return
+		}
+
+		p := &parser{onFragment: func(f *Fragment) { out <- f }}
+		p.scanLines(normalize(string(data)))
+	}()
+
+	return out
+}
+
 type parser struct {
-	// This determines if any 'visible' Fragment has been found.  Once any
-	// visible Fragment is found, stop looking for hidden ones.
-	foundVisible bool
 	// This instance variable points to the current Fragment.  If the matched
 	// line fits, it should be added to this Fragment.  Otherwise, finish it and
 	// start a new Fragment.
 	fragment *Fragment
-	// The fragments parsed so far
+	// The fragments parsed so far, in last-to-first (discovery) order until
+	// Parse restores reading order with reverseFragments.
 	fragments []*Fragment
+	// Additional locales whose quote-header, signature, and forwarded-message
+	// patterns are checked alongside the package defaults. Empty for Parse,
+	// populated for ParseWithLocales.
+	locales []Locale
+	// onFragment, if set, is called as soon as each Fragment is finished,
+	// before hidden Fragments have been determined. Used by ParseStream to
+	// send each Fragment on its channel as soon as it's finished.
+	onFragment func(*Fragment)
 }
 
-// > I define UNIX as “30 definitions of regular expressions living under one
-// > roof.”
-// —Don Knuth
-//
-// Porting the Ruby regular expressions from email_reply_parser to Go required
-// making the following changes:
-//
-// - Unlike most regexp flavors I'm familiar with, ^ and $ stand for beginning
-//   and end of line respectively in Ruby. Getting the same behavior in Go
-//   required enabling Go's multiline mode "(?m)" for these expressions.
-// - Ruby's multiline mode "/m" is the same as Go's "(?s)" flag. Both are used
-//   to make "." match "\n" characters.
 var (
 	// used to join quote headers that were broken into multiple lines by the
 	// e-mail client. e.g. gmail does that for lines exceeding 80 chars
@@ -53,18 +108,61 @@ var (
 		// e.g. 2013/11/13 John Smith <john@smith.org>
 		regexp.MustCompile("(?sm)^(\\d{4}/\\d{2}/\\d{2} .*<.+@.+>)$"),
 	}
-	sigRegexp         = regexp.MustCompile("(--|__|(?m)\\w-$)|(?m)(^(\\w+\\s*){1,3} " + reverseString("Sent from my") + "$)")
-	fwdRegexp         = regexp.MustCompile("(?mi)^--+\\s*" + reverseString("Forwarded message") + "\\s*--+$")
-	quotedRegexp      = regexp.MustCompile("(?m)(>+)$")
-	quoteHeaderRegexp = regexp.MustCompile("(?m)^:etorw.*nO$|^>.*\\d{2}/\\d{2}/\\d{4}$")
+	// sigRegexp recognizes the generic "--"/"__" conventions plus the mobile
+	// and desktop client signatures seen in the email_reply_parser fixture
+	// corpus: "Sent from my <device>" (allowing for longer device names like
+	// "Samsung Galaxy Note 10 Plus"), BlackBerry's "Sent via BlackBerry from
+	// ...", the Outlook mobile apps' "Get Outlook for iOS/Android", the
+	// Windows 10 Mail app, and the German/French phrasings of "sent from my
+	// device".
+	sigRegexp = regexp.MustCompile(`(--|__|(?m)^-\w)` +
+		`|(?m)^Sent from my(\s+\w+){1,10}$` +
+		`|(?m)^Sent via BlackBerry from\s.*$` +
+		`|(?m)^Get Outlook for (iOS|Android)$` +
+		`|(?m)^Sent from Mail for Windows 10$` +
+		`|(?m)^Von meinem.*$` +
+		`|(?m)^Envoyé de mon.*$`)
+	fwdRegexp         = regexp.MustCompile(`(?mi)^--+\s*Forwarded message\s*--+$`)
+	quotedRegexp      = regexp.MustCompile(`(?m)^(>+)`)
+	quoteHeaderRegexp = regexp.MustCompile(`(?m)^On\s.*wrote:$|^\d{4}/\d{2}/\d{2}.*>$`)
 )
 
+// Parse scans text for Fragments in two passes.
+//
+// Pass one, scanLines/scanLine/finishFragment, splits the text into
+// Fragments. It has to do that bottom-to-top: a trailing signature or a
+// quote header can only be recognized once the blank line (or end of
+// message) that follows it has been seen, so scanLine looks at each line
+// together with the one that follows it in the message, and the only way
+// to offer that look-ahead one line at a time is to walk the lines in
+// reverse and finish each Fragment's lines in the order they're visited.
+//
+// Pass two, markHidden, walks the resulting (now naturally-ordered) slice
+// from the last Fragment to the first to decide which are hidden: a
+// quoted/signature/empty Fragment below the last Fragment with original
+// content is hidden, since quotes above the author's reply give it
+// context, but quotes and signatures below it are noise.
+//
+// Earlier versions of this package reversed the entire text's characters up
+// front so that a single bottom-to-top pass could also double as the
+// hidden-fragment pass, at the cost of every regexp having to be written
+// backwards to match. Reversing just the order lines are visited in --
+// not their content -- gets the same look-ahead far more cheaply, and
+// lets every pattern in this package read the way the email actually does.
 func (p *parser) Parse(text string) Email {
-	// Normalize line endings.
+	p.scanLines(normalize(text))
+	reverseFragments(p.fragments)
+	markHidden(p.fragments)
+	return Email(p.fragments)
+}
+
+// normalize prepares raw email text for scanLines: it converts CRLF line
+// endings to LF, then joins back together any quote header that a client
+// broke across multiple lines (e.g. Gmail wrapping at 80 chars), so
+// scanLine sees it as the single line it's meant to be.
+func normalize(text string) string {
 	text = strings.Replace(text, "\r\n", "\n", -1)
 
-	// Check for multi-line reply headers. Some clients break up the "On DATE,
-	// NAME <EMAIL> wrote:" line (and similar quote headers) into multiple lines.
 	for _, r := range multiLineReplyHeaderRegexps {
 		if m := r.FindStringSubmatch(text); len(m) == 2 {
 			// Remove all new lines from the reply header.
@@ -72,38 +170,52 @@ func (p *parser) Parse(text string) Email {
 		}
 	}
 
-	// The text is reversed initially due to the way we check for hidden
-	// fragments.
-	text = reverseString(text)
-
-	// Use the Reader to pull out each line of the email content.
-	reader := bufio.NewReader(strings.NewReader(text))
-	for {
-		line, e := reader.ReadBytes('\n')
-		p.scanLine(strings.TrimRight(string(line), "\n"))
-		if e == io.EOF {
-			break
-		} else if e != nil {
-			// Our underlaying reader is a strings.Reader, which will never return
-			// errors other than io.EOF, so this is merely a sanity check.
-			panic(fmt.Sprintf("Bug: ReadBytes returned an error other than io.EOF: %#v", e))
-		}
-	}
+	return text
+}
 
-	// Finish up the final fragment.  Finishing a fragment will detect any
-	// attributes (hidden, signature, reply), and join each line into a
-	// string.
+// scanLines feeds text to scanLine one line at a time, last line first, and
+// finishes the final (textually first) Fragment once done. Fragments are
+// appended to p.fragments as they're finished, so afterwards p.fragments is
+// in last-to-first (discovery) order, not reading order; Parse restores
+// reading order with reverseFragments before returning.
+func (p *parser) scanLines(text string) {
+	lines := strings.Split(text, "\n")
+	for i := len(lines) - 1; i >= 0; i-- {
+		p.scanLine(lines[i])
+	}
 	p.finishFragment()
+}
 
-	// Now that parsing is done, reverse the order.
-	reverseFragments(p.fragments)
-	return Email(p.fragments)
+// reverseFragments reverses fragments in place, turning discovery
+// (last-to-first) order into reading (first-to-last) order.
+func reverseFragments(fragments []*Fragment) {
+	for i, j := 0, len(fragments)-1; i < j; i, j = i+1, j-1 {
+		fragments[i], fragments[j] = fragments[j], fragments[i]
+	}
+}
+
+// markHidden walks fragments from the last to the first, marking quoted,
+// signature, or empty ones as hidden until a Fragment with original content
+// is found; see Parse's doc comment for the reasoning.
+func markHidden(fragments []*Fragment) {
+	foundVisible := false
+	for i := len(fragments) - 1; i >= 0; i-- {
+		if foundVisible {
+			continue
+		}
+		f := fragments[i]
+		if f.quoted || f.signature || strings.TrimSpace(f.String()) == "" {
+			f.hidden = true
+		} else {
+			foundVisible = true
+		}
+	}
 }
 
 // scaneLine scans the given line of text and figures out which fragment it
 // belongs to.
 func (p *parser) scanLine(line string) {
-	sigMatch := sigRegexp.MatchString(line)
+	sigMatch := p.matchesSignature(line)
 
 	if !sigMatch {
 		line = strings.TrimLeftFunc(line, unicode.IsSpace)
@@ -116,13 +228,13 @@ func (p *parser) scanLine(line string) {
 	// Mark the current Fragment as a signature if the current line is empty
 	// and the Fragment starts with a common signature indicator.
 	if p.fragment != nil && line == "" {
-		// lastLine is really the first line, since the lines are still reversed
-		// at this point.
+		// lastLine is the line immediately below this blank one, i.e. the
+		// current Fragment's first line in reading order.
 		lastLine := p.fragment.lines[len(p.fragment.lines)-1]
-		if fwdRegexp.MatchString(lastLine) {
+		if p.matchesForwarded(lastLine) {
 			p.fragment.forwarded = true
 			p.finishFragment()
-		} else if sigRegexp.MatchString(lastLine) {
+		} else if p.matchesSignature(lastLine) {
 			p.fragment.signature = true
 			p.finishFragment()
 		}
@@ -155,17 +267,55 @@ func (p *parser) scanLine(line string) {
 // only checked for lines preceding quoted regions. Returns true if the line is
 // a valid header, or false.
 func (p *parser) quoteHeader(line string) bool {
-	return quoteHeaderRegexp.MatchString(line)
+	return p.matchesQuoteHeader(line)
 }
 
-// finishFragment builds the fragment string and reverses it, after all lines
-// have been added.  It also checks to see if this Fragment is hidden.  The
-// hidden Fragment check reads from the bottom to the top.
-//
-// Any quoted Fragments or signature Fragments are marked hidden if they are
-// below any visible Fragments.  Visible Fragments are expected to contain
-// original content by the author.  If they are below a quoted Fragment, then
-// the Fragment should be visible to give context to the reply.
+// matchesQuoteHeader reports whether line matches the package's built-in
+// quote header pattern or any of p.locales'.
+func (p *parser) matchesQuoteHeader(line string) bool {
+	if quoteHeaderRegexp.MatchString(line) {
+		return true
+	}
+	for _, loc := range p.locales {
+		if loc.QuoteHeader != nil && loc.QuoteHeader.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesSignature reports whether line matches the package's built-in
+// signature pattern or any of p.locales'.
+func (p *parser) matchesSignature(line string) bool {
+	if sigRegexp.MatchString(line) {
+		return true
+	}
+	for _, loc := range p.locales {
+		if loc.Signature != nil && loc.Signature.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesForwarded reports whether line matches the package's built-in
+// forwarded-message banner pattern or any of p.locales'.
+func (p *parser) matchesForwarded(line string) bool {
+	if fwdRegexp.MatchString(line) {
+		return true
+	}
+	for _, loc := range p.locales {
+		if loc.Forwarded != nil && loc.Forwarded.MatchString(line) {
+			return true
+		}
+	}
+	return false
+}
+
+// finishFragment builds the fragment string after all lines have been
+// added, and appends it to p.fragments (and also passes it to p.onFragment,
+// if set, for ParseStream). Whether the Fragment is hidden isn't known yet -- that's
+// markHidden's job, once the whole text has been scanned.
 //
 //     some original text (visible)
 //
@@ -179,33 +329,14 @@ func (p *parser) quoteHeader(line string) bool {
 func (p *parser) finishFragment() {
 	if p.fragment != nil {
 		p.fragment.finish()
-		if !p.foundVisible {
-			if p.fragment.quoted || p.fragment.signature ||
-				strings.TrimSpace(p.fragment.String()) == "" {
-				p.fragment.hidden = true
-			} else {
-				p.foundVisible = true
-			}
-		}
 		p.fragments = append(p.fragments, p.fragment)
+		if p.onFragment != nil {
+			p.onFragment(p.fragment)
+		}
 	}
 	p.fragment = nil
 }
 
-func reverseString(s string) string {
-	runes := []rune(s)
-	for i, j := 0, len(runes)-1; i < j; i, j = i+1, j-1 {
-		runes[i], runes[j] = runes[j], runes[i]
-	}
-	return string(runes)
-}
-
-func reverseFragments(f []*Fragment) {
-	for i, j := 0, len(f)-1; i < j; i, j = i+1, j-1 {
-		f[i], f[j] = f[j], f[i]
-	}
-}
-
 // Email contains the parsed contents of an email.
 type Email []*Fragment
 
@@ -225,21 +356,52 @@ func (e Email) String() string {
 	return result
 }
 
+// HTML returns the HTML markup of the non-Hidden() fragments of the Email,
+// joined in document order. It is only populated for emails parsed with
+// ParseHTML, or with ParseMessage given an HTML content type.
+func (e Email) HTML() string {
+	results := []string{}
+	for _, fragment := range e {
+		if fragment.Hidden() {
+			continue
+		}
+
+		results = append(results, fragment.HTML())
+	}
+
+	return strings.Join(results, "\n")
+}
+
 // Fragment contains a parsed section of an email.
 type Fragment struct {
-	lines     []string
-	content   string
-	hidden    bool
-	signature bool
-	forwarded bool
-	quoted    bool
+	lines       []string
+	content     string
+	htmlContent string
+	header      QuoteHeader
+	hidden      bool
+	signature   bool
+	forwarded   bool
+	quoted      bool
 }
 
-// finish builds the string content by joining the lines and reversing them.
+// finish builds the string content by joining the lines. f.lines is in
+// discovery (last-to-first) order, since scanLine appends to it while
+// scanLines walks the text bottom to top, so it has to be reversed back
+// into reading order first.
 func (f *Fragment) finish() {
+	reverseLines(f.lines)
 	f.content = strings.Join(f.lines, "\n")
 	f.lines = nil
-	f.content = reverseString(f.content)
+	if f.quoted {
+		f.header = parseQuoteHeader(f.content)
+	}
+}
+
+// reverseLines reverses lines in place.
+func reverseLines(lines []string) {
+	for i, j := 0, len(lines)-1; i < j; i, j = i+1, j-1 {
+		lines[i], lines[j] = lines[j], lines[i]
+	}
 }
 
 // Forwarded returns if the fragment is forwarded or not.
@@ -262,7 +424,21 @@ func (f *Fragment) Hidden() bool {
 	return f.hidden
 }
 
+// QuoteHeader returns the sender attribution mailstrip parsed out of this
+// Fragment's header line. It's the zero QuoteHeader if the Fragment isn't
+// Quoted(), or its header didn't match a known shape.
+func (f *Fragment) QuoteHeader() QuoteHeader {
+	return f.header
+}
+
 // String returns the content of the fragment.
 func (f *Fragment) String() string {
 	return f.content
 }
+
+// HTML returns the HTML markup of the fragment. It is only populated for
+// fragments produced by ParseHTML, or by ParseMessage given an HTML content
+// type; fragments produced by Parse or ParseWithLocales leave it empty.
+func (f *Fragment) HTML() string {
+	return f.htmlContent
+}
@@ -90,6 +90,47 @@ I am currently using the Java HTTP API.
 			&contentChecker{1, regexp.MustCompile("Was this")},
 		},
 	},
+	{
+		"test_recognizes_outlook_mobile_signature",
+		"email_1_8",
+		[]checker{
+			&attributeChecker{"Signature", []bool{false, true}},
+			&attributeChecker{"Hidden", []bool{false, true}},
+			&contentChecker{1, regexp.MustCompile("(?m)^Get Outlook for iOS")},
+		},
+	},
+	{
+		"test_recognizes_blackberry_signature",
+		"email_BlackBerry",
+		[]checker{
+			&attributeChecker{"Signature", []bool{false, true}},
+			&attributeChecker{"Hidden", []bool{false, true}},
+			&contentChecker{1, regexp.MustCompile("(?m)^Sent via BlackBerry from")},
+		},
+	},
+	{
+		"test_recognizes_localized_iphone_signature",
+		"email_iPhone",
+		[]checker{
+			&attributeChecker{"Signature", []bool{false, true}},
+			&attributeChecker{"Hidden", []bool{false, true}},
+			&contentChecker{1, regexp.MustCompile("(?m)^Envoyé de mon iPhone")},
+		},
+	},
+	{
+		"test_recognizes_multi_word_sent_from_my_device_signature",
+		"email_multi_word_sent_from_my_device",
+		[]checker{
+			&attributeChecker{"Signature", []bool{false, true}},
+			&attributeChecker{"Hidden", []bool{false, true}},
+			&contentChecker{1, regexp.MustCompile("(?m)^Sent from my Samsung Galaxy")},
+		},
+	},
+	{
+		"test_does_not_treat_bullets_as_a_signature",
+		"email_bullets",
+		[]checker{fragmentCountChecker(1)},
+	},
 }
 
 func TestParse(t *testing.T) {
@@ -101,11 +142,7 @@ func TestParse(t *testing.T) {
 			continue
 		}
 
-		parsed, err := Parse(text)
-		if err != nil {
-			t.Error(err)
-			continue
-		}
+		parsed := Parse(text)
 
 		for _, check := range test.checks {
 			if err := check.Check(parsed); err != nil {
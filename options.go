@@ -0,0 +1,77 @@
+package mailstrip
+
+import (
+	"strings"
+	"unicode"
+)
+
+// ParseOptions configures optional parser behaviors beyond what Parse does
+// by default. The zero value behaves exactly like Parse.
+type ParseOptions struct {
+	// HideEverythingAfter lists line prefixes that mark the start of quoted
+	// or forwarded content that has no other markup mailstrip can detect,
+	// such as Outlook's "-----Original Message-----" banner or a bare
+	// "From:"/"Sent:"/"To:"/"Subject:" header block. The first line of text
+	// that starts with one of these prefixes, and everything after it, is
+	// placed into a single Fragment flagged both Quoted() and Hidden().
+	//
+	// This covers the common case of clients that don't prefix quoted
+	// content with "> " at all.
+	HideEverythingAfter []string
+
+	// ExceptInVisibleBlockQuotes, when true, skips the
+	// HideEverythingAfter cutoff for lines that are themselves part of a
+	// quoted ("> ...") block, so a quote the user is meant to see isn't
+	// truncated just because it contains, say, a forwarded "From:" line.
+	ExceptInVisibleBlockQuotes bool
+}
+
+// ParseWithOptions parses a plaintext email the same way Parse does, but
+// additionally applies opts.HideEverythingAfter: once a line starting with
+// one of those prefixes is seen, it and everything below it is dropped from
+// Email.String() by placing it in its own hidden Fragment.
+func ParseWithOptions(text string, opts ParseOptions) Email {
+	text = strings.Replace(text, "\r\n", "\n", -1)
+
+	idx := cutoffIndex(text, opts)
+	if idx < 0 {
+		return Parse(text)
+	}
+
+	email := Parse(text[:idx])
+	content := strings.TrimRightFunc(text[idx:], unicode.IsSpace)
+	cutoff := &Fragment{
+		content: content,
+		quoted:  true,
+		hidden:  true,
+		header:  parseQuoteHeader(content),
+	}
+	return append(email, cutoff)
+}
+
+// cutoffIndex returns the byte offset in text of the first line matching one
+// of opts.HideEverythingAfter, or -1 if none is found.
+func cutoffIndex(text string, opts ParseOptions) int {
+	if len(opts.HideEverythingAfter) == 0 {
+		return -1
+	}
+
+	offset := 0
+	for _, line := range strings.SplitAfter(text, "\n") {
+		trimmed := strings.TrimLeftFunc(strings.TrimRight(line, "\n"), unicode.IsSpace)
+
+		if opts.ExceptInVisibleBlockQuotes && strings.HasPrefix(trimmed, ">") {
+			offset += len(line)
+			continue
+		}
+
+		for _, prefix := range opts.HideEverythingAfter {
+			if strings.HasPrefix(trimmed, prefix) {
+				return offset
+			}
+		}
+
+		offset += len(line)
+	}
+	return -1
+}
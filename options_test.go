@@ -0,0 +1,49 @@
+package mailstrip
+
+import "testing"
+
+func TestParseWithOptionsHideEverythingAfter(t *testing.T) {
+	text := `Hi,
+
+See below.
+
+-----Original Message-----
+From: Bob
+Sent: today
+To: Alice
+Subject: Re: stuff
+
+The original body, with no > markers at all.
+`
+
+	email := ParseWithOptions(text, ParseOptions{
+		HideEverythingAfter: []string{"-----Original Message-----"},
+	})
+
+	if got, want := email.String(), "Hi,\n\nSee below."; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+
+	last := email[len(email)-1]
+	if !last.Quoted() || !last.Hidden() {
+		t.Errorf("last fragment: Quoted() = %t, Hidden() = %t, want true, true", last.Quoted(), last.Hidden())
+	}
+}
+
+func TestParseWithOptionsExceptInVisibleBlockQuotes(t *testing.T) {
+	text := `Hi,
+
+> From: Bob, forwarded inside a quote we want to keep
+
+Thanks!
+`
+
+	email := ParseWithOptions(text, ParseOptions{
+		HideEverythingAfter:        []string{"From:"},
+		ExceptInVisibleBlockQuotes: true,
+	})
+
+	if got, want := email.String(), text[:len(text)-1]; got != want {
+		t.Errorf("String() = %q, want %q (cutoff should not trigger inside a quoted line)", got, want)
+	}
+}
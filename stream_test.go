@@ -0,0 +1,109 @@
+package mailstrip
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseReaderMatchesParse(t *testing.T) {
+	text := "Thanks!\n\nOn Jan 2, 2011, Alice <alice@example.com> wrote:\n> Can we push the release?\n"
+
+	email, err := ParseReader(strings.NewReader(text))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got, want := email.String(), Parse(text).String(); got != want {
+		t.Errorf("ParseReader = %q, want %q", got, want)
+	}
+}
+
+func TestParseStreamDeliversFragments(t *testing.T) {
+	text := "Yeah, that works!\n\n-Bob\n\nOn Jan 3, 2011, Alice <alice@example.com> wrote:\n> Can we push the release?\n"
+
+	errs := make(chan error, 1)
+	var got []*Fragment
+	for f := range ParseStream(strings.NewReader(text), errs) {
+		got = append(got, f)
+	}
+	select {
+	case err := <-errs:
+		t.Fatalf("unexpected error: %v", err)
+	default:
+	}
+
+	if len(got) != 4 {
+		t.Fatalf("got %d fragments, want 4: %+v", len(got), got)
+	}
+
+	// Fragments arrive last-to-first, in discovery order. The first is the
+	// empty Fragment for the trailing blank line the text ends with.
+	if got, want := got[0].String(), ""; got != want {
+		t.Errorf("first fragment = %q, want %q", got, want)
+	}
+	if got, want := got[1].String(), "\nOn Jan 3, 2011, Alice <alice@example.com> wrote:\n> Can we push the release?"; got != want {
+		t.Errorf("second fragment = %q, want %q", got, want)
+	}
+	if got, want := got[2].String(), "-Bob"; got != want {
+		t.Errorf("third fragment = %q, want %q", got, want)
+	}
+	if got, want := got[3].String(), "Yeah, that works!\n"; got != want {
+		t.Errorf("fourth fragment = %q, want %q", got, want)
+	}
+
+	for _, f := range got {
+		if f.Hidden() {
+			t.Errorf("streamed fragment %q reported Hidden() = true, want always false", f.String())
+		}
+	}
+}
+
+func TestParseStreamNormalizesLikeParse(t *testing.T) {
+	// CRLF line endings, plus a quote header Gmail-style wrapped across two
+	// lines: ParseStream has to apply the same normalization Parse does
+	// before scanning, or the header's "wrote:" won't even be recognized.
+	text := "Thanks!\r\n\r\nOn Aug 22, 2011, at 7:37 PM, defunkt<reply@reply.github.com>\r\nwrote:\r\n> hi\r\n"
+
+	errs := make(chan error, 1)
+	var got []*Fragment
+	for f := range ParseStream(strings.NewReader(text), errs) {
+		got = append(got, f)
+	}
+	select {
+	case err := <-errs:
+		t.Fatalf("unexpected error: %v", err)
+	default:
+	}
+
+	want := Parse(text)
+	if len(got) != len(want) {
+		t.Fatalf("got %d fragments, want %d", len(got), len(want))
+	}
+	// want is in reading order; got arrives last-to-first.
+	for i, f := range got {
+		if wf := want[len(want)-1-i]; f.String() != wf.String() || f.Quoted() != wf.Quoted() {
+			t.Errorf("fragment %d = (quoted=%v, %q), want (quoted=%v, %q)", i, f.Quoted(), f.String(), wf.Quoted(), wf.String())
+		}
+	}
+}
+
+func TestParseStreamReportsReadError(t *testing.T) {
+	boom := fmtError("boom")
+	errs := make(chan error, 1)
+	for range ParseStream(errReader{boom}, errs) {
+		t.Fatal("expected no fragments on read error")
+	}
+	if err := <-errs; err != boom {
+		t.Errorf("got error %v, want %v", err, boom)
+	}
+}
+
+type fmtError string
+
+func (e fmtError) Error() string { return string(e) }
+
+type errReader struct{ err error }
+
+func (r errReader) Read(p []byte) (int, error) {
+	return 0, r.err
+}